@@ -0,0 +1,233 @@
+// Package auth provides JWT-based authentication and per-user todo
+// ownership: user registration/login, a bearer-token middleware, and
+// helpers for threading the authenticated user id through request
+// contexts.
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/NLstn/todo_backend/logger"
+	"github.com/golang-jwt/jwt/v5"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// User is stored in the users collection.
+type User struct {
+	ID           primitive.ObjectID `bson:"_id,omitempty" json:"id,omitempty"`
+	Email        string             `bson:"email" json:"email"`
+	PasswordHash string             `bson:"password_hash" json:"-"`
+}
+
+type contextKey int
+
+const userIDContextKey contextKey = iota
+
+// CookieName is the cookie Authenticate falls back to when a request
+// carries no Authorization header, for browser flows such as the ui
+// package that can't set custom headers on a plain form submission.
+const CookieName = "auth_token"
+
+// ErrInvalidCredentials is returned by VerifyCredentials when the email
+// is unknown or the password doesn't match.
+var ErrInvalidCredentials = errors.New("invalid email or password")
+
+// Service issues and validates JWTs and handles user registration/login
+// against the users collection.
+type Service struct {
+	Users  *mongo.Collection
+	Secret []byte
+	TTL    time.Duration
+}
+
+// NewService builds a Service backed by the given users collection.
+func NewService(users *mongo.Collection, secret string, ttl time.Duration) *Service {
+	return &Service{Users: users, Secret: []byte(secret), TTL: ttl}
+}
+
+// EnsureIndexes creates the indexes Service relies on, notably a unique
+// index on email so two users can never register the same address. Call
+// this once at startup.
+func (s *Service) EnsureIndexes(ctx context.Context) error {
+	_, err := s.Users.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "email", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+	return err
+}
+
+type credentials struct {
+	Email    string `json:"email"`
+	Password string `json:"password"`
+}
+
+// CreateUser hashes password and inserts a new user document. If email is
+// already registered it returns the insert error, which
+// mongo.IsDuplicateKeyError reports as a duplicate key.
+func (s *Service) CreateUser(ctx context.Context, email, password string) (primitive.ObjectID, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return primitive.NilObjectID, err
+	}
+
+	user := User{Email: email, PasswordHash: string(hash)}
+	result, err := s.Users.InsertOne(ctx, user)
+	if err != nil {
+		return primitive.NilObjectID, err
+	}
+
+	return result.InsertedID.(primitive.ObjectID), nil
+}
+
+// VerifyCredentials looks up email and checks password against its stored
+// hash, returning ErrInvalidCredentials if either doesn't match.
+func (s *Service) VerifyCredentials(ctx context.Context, email, password string) (primitive.ObjectID, error) {
+	var user User
+	err := s.Users.FindOne(ctx, bson.M{"email": email}).Decode(&user)
+	if err != nil {
+		if err != mongo.ErrNoDocuments {
+			logger.FromContext(ctx).Error("find user failed", "error", err)
+		}
+		return primitive.NilObjectID, ErrInvalidCredentials
+	}
+
+	if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(password)); err != nil {
+		return primitive.NilObjectID, ErrInvalidCredentials
+	}
+
+	return user.ID, nil
+}
+
+// Register handles POST /v1/auth/register.
+func (s *Service) Register(w http.ResponseWriter, r *http.Request) {
+	var creds credentials
+	if err := json.NewDecoder(r.Body).Decode(&creds); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	defer r.Body.Close()
+
+	if creds.Email == "" || creds.Password == "" {
+		http.Error(w, "email and password are required", http.StatusBadRequest)
+		return
+	}
+
+	userID, err := s.CreateUser(r.Context(), creds.Email, creds.Password)
+	if mongo.IsDuplicateKeyError(err) {
+		http.Error(w, "email already registered", http.StatusConflict)
+		return
+	}
+	if err != nil {
+		logger.FromContext(r.Context()).Error("insert user failed", "error", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	token, err := s.IssueToken(userID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(map[string]string{"token": token})
+}
+
+// Login handles POST /v1/auth/login.
+func (s *Service) Login(w http.ResponseWriter, r *http.Request) {
+	var creds credentials
+	if err := json.NewDecoder(r.Body).Decode(&creds); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	defer r.Body.Close()
+
+	userID, err := s.VerifyCredentials(r.Context(), creds.Email, creds.Password)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	token, err := s.IssueToken(userID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{"token": token})
+}
+
+// IssueToken signs a JWT for userID using Service's secret and TTL.
+func (s *Service) IssueToken(userID primitive.ObjectID) (string, error) {
+	now := time.Now()
+	claims := jwt.RegisteredClaims{
+		Subject:   userID.Hex(),
+		IssuedAt:  jwt.NewNumericDate(now),
+		ExpiresAt: jwt.NewNumericDate(now.Add(s.TTL)),
+	}
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	return token.SignedString(s.Secret)
+}
+
+// Authenticate validates the request's bearer token, falling back to the
+// CookieName cookie for browser flows that can't set custom headers, and
+// injects the authenticated user id into the request context. It responds
+// 401 for a missing or invalid token.
+func (s *Service) Authenticate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		tokenStr := s.tokenFromRequest(r)
+		if tokenStr == "" {
+			http.Error(w, "missing bearer token", http.StatusUnauthorized)
+			return
+		}
+
+		var claims jwt.RegisteredClaims
+		_, err := jwt.ParseWithClaims(tokenStr, &claims, func(t *jwt.Token) (interface{}, error) {
+			if _, ok := t.Method.(*jwt.SigningMethodHMAC); !ok {
+				return nil, errors.New("unexpected signing method")
+			}
+			return s.Secret, nil
+		})
+		if err != nil {
+			http.Error(w, "invalid or expired token", http.StatusUnauthorized)
+			return
+		}
+
+		userID, err := primitive.ObjectIDFromHex(claims.Subject)
+		if err != nil {
+			http.Error(w, "invalid token subject", http.StatusUnauthorized)
+			return
+		}
+
+		ctx := context.WithValue(r.Context(), userIDContextKey, userID)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+func (s *Service) tokenFromRequest(r *http.Request) string {
+	if tokenStr, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer "); ok && tokenStr != "" {
+		return tokenStr
+	}
+	if cookie, err := r.Cookie(CookieName); err == nil {
+		return cookie.Value
+	}
+	return ""
+}
+
+// UserIDFromContext returns the authenticated user id stored by
+// Authenticate, if any.
+func UserIDFromContext(ctx context.Context) (primitive.ObjectID, bool) {
+	userID, ok := ctx.Value(userIDContextKey).(primitive.ObjectID)
+	return userID, ok
+}
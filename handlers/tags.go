@@ -0,0 +1,87 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/NLstn/todo_backend/auth"
+	"github.com/NLstn/todo_backend/logger"
+	"github.com/gorilla/mux"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// GetTag handles GET /v1/todos/{id}/tags/{tag} and reports whether the tag
+// is set on the todo.
+func (h *TodoHandler) GetTag(w http.ResponseWriter, r *http.Request) {
+	ownerID, _ := auth.UserIDFromContext(r.Context())
+	vars := mux.Vars(r)
+
+	id, err := primitive.ObjectIDFromHex(vars["id"])
+	if err != nil {
+		http.Error(w, "id must be an object id", http.StatusBadRequest)
+		return
+	}
+	tag := vars["tag"]
+
+	var todo Todo
+	err = h.Collection.FindOne(r.Context(), bson.M{"_id": id}).Decode(&todo)
+	if WriteFindErr(w, r, err) {
+		return
+	}
+	if todo.OwnerID != ownerID {
+		http.Error(w, "forbidden", http.StatusForbidden)
+		return
+	}
+
+	for _, t := range todo.Tags {
+		if t == tag {
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(todo)
+			return
+		}
+	}
+
+	http.Error(w, "tag not found", http.StatusNotFound)
+}
+
+// SetTag handles POST /v1/todos/{id}/tags/{tag} and adds the tag to the
+// todo if it isn't already present.
+func (h *TodoHandler) SetTag(w http.ResponseWriter, r *http.Request) {
+	ownerID, _ := auth.UserIDFromContext(r.Context())
+	vars := mux.Vars(r)
+
+	id, err := primitive.ObjectIDFromHex(vars["id"])
+	if err != nil {
+		http.Error(w, "id must be an object id", http.StatusBadRequest)
+		return
+	}
+	tag := vars["tag"]
+
+	var existing Todo
+	err = h.Collection.FindOne(r.Context(), bson.M{"_id": id}).Decode(&existing)
+	if WriteFindErr(w, r, err) {
+		return
+	}
+	if existing.OwnerID != ownerID {
+		http.Error(w, "forbidden", http.StatusForbidden)
+		return
+	}
+
+	var updated Todo
+	err = h.Collection.FindOneAndUpdate(
+		r.Context(),
+		bson.M{"_id": id, "owner_id": ownerID},
+		bson.M{"$addToSet": bson.M{"tags": tag}},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&updated)
+	if err != nil {
+		logger.FromContext(r.Context()).Error("set tag failed", "error", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(updated)
+}
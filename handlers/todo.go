@@ -0,0 +1,318 @@
+// Package handlers contains the HTTP handlers for the todo API, split out
+// of main.go so the router package can wire them up independently of
+// transport concerns.
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/NLstn/todo_backend/auth"
+	"github.com/NLstn/todo_backend/logger"
+	"github.com/gorilla/mux"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Todo is a single todo item, scoped to the user that created it.
+type Todo struct {
+	ID          primitive.ObjectID `bson:"_id,omitempty" json:"id,omitempty"`
+	Description string             `bson:"description" json:"description"`
+	OwnerID     primitive.ObjectID `bson:"owner_id" json:"owner_id"`
+	Tags        []string           `bson:"tags,omitempty" json:"tags,omitempty"`
+}
+
+// ListResponse is the envelope returned by GET /v1/todos.
+type ListResponse struct {
+	Items  []Todo `json:"items"`
+	Total  int64  `json:"total"`
+	Limit  int64  `json:"limit"`
+	Offset int64  `json:"offset"`
+}
+
+const (
+	defaultLimit = 20
+	maxLimit     = 100
+)
+
+var mutex = &sync.Mutex{}
+
+// WriteFindErr inspects the error from a Mongo FindOne/Decode call and
+// writes the appropriate response: 404 if the document doesn't exist, 500
+// (with the error logged) otherwise. It returns true if it wrote a
+// response, so callers can `if WriteFindErr(...) { return }`.
+func WriteFindErr(w http.ResponseWriter, r *http.Request, err error) bool {
+	if err == nil {
+		return false
+	}
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		http.Error(w, "todo not found", http.StatusNotFound)
+		return true
+	}
+	logger.FromContext(r.Context()).Error("find todo failed", "error", err)
+	http.Error(w, err.Error(), http.StatusInternalServerError)
+	return true
+}
+
+// TodoHandler serves the /v1/todos routes against a single Mongo collection.
+type TodoHandler struct {
+	Collection *mongo.Collection
+}
+
+// NewTodoHandler builds a TodoHandler backed by the given collection.
+func NewTodoHandler(collection *mongo.Collection) *TodoHandler {
+	return &TodoHandler{Collection: collection}
+}
+
+// Create handles POST /v1/todos.
+func (h *TodoHandler) Create(w http.ResponseWriter, r *http.Request) {
+	ownerID, _ := auth.UserIDFromContext(r.Context())
+
+	var todo Todo
+	if err := json.NewDecoder(r.Body).Decode(&todo); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	defer r.Body.Close()
+
+	mutex.Lock()
+	result, err := h.Collection.InsertOne(r.Context(), bson.M{"description": todo.Description, "owner_id": ownerID})
+	mutex.Unlock()
+	if err != nil {
+		logger.FromContext(r.Context()).Error("insert todo failed", "error", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	todo.ID = result.InsertedID.(primitive.ObjectID)
+	todo.OwnerID = ownerID
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(todo)
+}
+
+// Get handles GET /v1/todos/{id}.
+func (h *TodoHandler) Get(w http.ResponseWriter, r *http.Request) {
+	ownerID, _ := auth.UserIDFromContext(r.Context())
+
+	id, err := primitive.ObjectIDFromHex(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "id must be an object id", http.StatusBadRequest)
+		return
+	}
+
+	var todo Todo
+	err = h.Collection.FindOne(r.Context(), bson.M{"_id": id}).Decode(&todo)
+	if WriteFindErr(w, r, err) {
+		return
+	}
+
+	if todo.OwnerID != ownerID {
+		http.Error(w, "forbidden", http.StatusForbidden)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(todo)
+}
+
+// Update handles PATCH /v1/todos/{id}.
+func (h *TodoHandler) Update(w http.ResponseWriter, r *http.Request) {
+	ownerID, _ := auth.UserIDFromContext(r.Context())
+
+	id, err := primitive.ObjectIDFromHex(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid ID", http.StatusBadRequest)
+		return
+	}
+
+	var existing Todo
+	err = h.Collection.FindOne(r.Context(), bson.M{"_id": id}).Decode(&existing)
+	if WriteFindErr(w, r, err) {
+		return
+	}
+	if existing.OwnerID != ownerID {
+		http.Error(w, "forbidden", http.StatusForbidden)
+		return
+	}
+
+	var todo Todo
+	if err := json.NewDecoder(r.Body).Decode(&todo); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	defer r.Body.Close()
+
+	var updatedTodo Todo
+	err = h.Collection.FindOneAndUpdate(
+		r.Context(),
+		bson.M{"_id": id, "owner_id": ownerID},
+		bson.M{"$set": bson.M{"description": todo.Description}},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&updatedTodo)
+	if err != nil {
+		logger.FromContext(r.Context()).Error("update todo failed", "error", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(updatedTodo)
+}
+
+// Delete handles DELETE /v1/todos/{id}.
+func (h *TodoHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	ownerID, _ := auth.UserIDFromContext(r.Context())
+
+	id, err := primitive.ObjectIDFromHex(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid ID", http.StatusBadRequest)
+		return
+	}
+
+	var existing Todo
+	err = h.Collection.FindOne(r.Context(), bson.M{"_id": id}).Decode(&existing)
+	if WriteFindErr(w, r, err) {
+		return
+	}
+	if existing.OwnerID != ownerID {
+		http.Error(w, "forbidden", http.StatusForbidden)
+		return
+	}
+
+	result, err := h.Collection.DeleteOne(r.Context(), bson.M{"_id": id, "owner_id": ownerID})
+	if err != nil {
+		logger.FromContext(r.Context()).Error("delete todo failed", "error", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	if result.DeletedCount == 0 {
+		http.Error(w, "todo not found", http.StatusNotFound)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// List handles GET /v1/todos?limit=&offset=&q=&sort=.
+func (h *TodoHandler) List(w http.ResponseWriter, r *http.Request) {
+	ownerID, _ := auth.UserIDFromContext(r.Context())
+
+	limit, offset, err := parsePaging(r.URL.Query())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	filter := bson.M{"owner_id": ownerID}
+	if q := r.URL.Query().Get("q"); q != "" {
+		filter["description"] = bson.M{"$regex": regexp.QuoteMeta(q), "$options": "i"}
+	}
+
+	findOptions := options.Find().SetLimit(limit).SetSkip(offset)
+	if sort := r.URL.Query().Get("sort"); sort != "" {
+		sortDoc, err := parseSort(sort)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		findOptions.SetSort(sortDoc)
+	}
+
+	total, err := h.Collection.CountDocuments(r.Context(), filter)
+	if err != nil {
+		logger.FromContext(r.Context()).Error("count todos failed", "error", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	rows, err := h.Collection.Find(r.Context(), filter, findOptions)
+	if err != nil {
+		logger.FromContext(r.Context()).Error("list todos failed", "error", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	defer rows.Close(r.Context())
+
+	todos := []Todo{}
+	for rows.Next(r.Context()) {
+		var todo Todo
+		if err := rows.Decode(&todo); err != nil {
+			logger.FromContext(r.Context()).Error("decode todo failed", "error", err)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		todos = append(todos, todo)
+	}
+
+	w.Header().Set("X-Total-Count", strconv.FormatInt(total, 10))
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(ListResponse{
+		Items:  todos,
+		Total:  total,
+		Limit:  limit,
+		Offset: offset,
+	})
+}
+
+func parsePaging(query map[string][]string) (limit, offset int64, err error) {
+	limit = defaultLimit
+	if v := firstOrEmpty(query["limit"]); v != "" {
+		limit, err = strconv.ParseInt(v, 10, 64)
+		if err != nil || limit < 1 {
+			return 0, 0, &paramError{"limit must be a positive integer"}
+		}
+		if limit > maxLimit {
+			limit = maxLimit
+		}
+	}
+
+	if v := firstOrEmpty(query["offset"]); v != "" {
+		offset, err = strconv.ParseInt(v, 10, 64)
+		if err != nil || offset < 0 {
+			return 0, 0, &paramError{"offset must be a non-negative integer"}
+		}
+	}
+
+	return limit, offset, nil
+}
+
+func parseSort(sort string) (bson.D, error) {
+	field, direction, found := strings.Cut(sort, ":")
+	if field == "" {
+		return nil, &paramError{"sort must be in the form field:asc|desc"}
+	}
+
+	order := 1
+	if found {
+		switch direction {
+		case "asc":
+			order = 1
+		case "desc":
+			order = -1
+		default:
+			return nil, &paramError{"sort direction must be asc or desc"}
+		}
+	}
+
+	return bson.D{{Key: field, Value: order}}, nil
+}
+
+func firstOrEmpty(values []string) string {
+	if len(values) == 0 {
+		return ""
+	}
+	return values[0]
+}
+
+type paramError struct{ msg string }
+
+func (e *paramError) Error() string { return e.msg }
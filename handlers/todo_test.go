@@ -0,0 +1,120 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+func TestParsePaging(t *testing.T) {
+	tests := []struct {
+		name       string
+		query      string
+		wantLimit  int64
+		wantOffset int64
+		wantErr    bool
+	}{
+		{name: "defaults", query: "", wantLimit: defaultLimit, wantOffset: 0},
+		{name: "explicit limit and offset", query: "limit=5&offset=10", wantLimit: 5, wantOffset: 10},
+		{name: "limit clamped to max", query: "limit=1000", wantLimit: maxLimit, wantOffset: 0},
+		{name: "non-numeric limit", query: "limit=abc", wantErr: true},
+		{name: "negative limit", query: "limit=-1", wantErr: true},
+		{name: "negative offset", query: "offset=-1", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query, err := url.ParseQuery(tt.query)
+			if err != nil {
+				t.Fatalf("invalid test query: %v", err)
+			}
+
+			limit, offset, err := parsePaging(query)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected an error, got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if limit != tt.wantLimit {
+				t.Errorf("limit = %d, want %d", limit, tt.wantLimit)
+			}
+			if offset != tt.wantOffset {
+				t.Errorf("offset = %d, want %d", offset, tt.wantOffset)
+			}
+		})
+	}
+}
+
+func TestWriteFindErr(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		wantCode int
+		wantWrit bool
+	}{
+		{name: "no error", err: nil, wantWrit: false},
+		{name: "not found", err: mongo.ErrNoDocuments, wantCode: http.StatusNotFound, wantWrit: true},
+		{name: "similar message is not treated as not-found", err: errors.New("decode: " + mongo.ErrNoDocuments.Error()), wantCode: http.StatusInternalServerError, wantWrit: true},
+		{name: "other error", err: errors.New("connection refused"), wantCode: http.StatusInternalServerError, wantWrit: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+
+			got := WriteFindErr(rec, req, tt.err)
+			if got != tt.wantWrit {
+				t.Fatalf("WriteFindErr() = %v, want %v", got, tt.wantWrit)
+			}
+			if !tt.wantWrit {
+				return
+			}
+			if rec.Code != tt.wantCode {
+				t.Errorf("status = %d, want %d", rec.Code, tt.wantCode)
+			}
+		})
+	}
+}
+
+func TestParseSort(t *testing.T) {
+	tests := []struct {
+		name    string
+		sort    string
+		want    bson.D
+		wantErr bool
+	}{
+		{name: "field only defaults to ascending", sort: "description", want: bson.D{{Key: "description", Value: 1}}},
+		{name: "ascending", sort: "description:asc", want: bson.D{{Key: "description", Value: 1}}},
+		{name: "descending", sort: "description:desc", want: bson.D{{Key: "description", Value: -1}}},
+		{name: "missing field", sort: ":asc", wantErr: true},
+		{name: "invalid direction", sort: "description:sideways", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseSort(tt.sort)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected an error, got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(got) != len(tt.want) || got[0].Key != tt.want[0].Key || got[0].Value != tt.want[0].Value {
+				t.Errorf("parseSort(%q) = %v, want %v", tt.sort, got, tt.want)
+			}
+		})
+	}
+}
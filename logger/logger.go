@@ -0,0 +1,26 @@
+// Package logger provides access to the per-request *slog.Logger stashed
+// in a request's context by middleware.RequestID.
+package logger
+
+import (
+	"context"
+	"log/slog"
+)
+
+type contextKey int
+
+const loggerContextKey contextKey = iota
+
+// NewContext returns a copy of ctx carrying l, retrievable with FromContext.
+func NewContext(ctx context.Context, l *slog.Logger) context.Context {
+	return context.WithValue(ctx, loggerContextKey, l)
+}
+
+// FromContext returns the logger stored in ctx, or slog.Default() if none
+// was stored.
+func FromContext(ctx context.Context) *slog.Logger {
+	if l, ok := ctx.Value(loggerContextKey).(*slog.Logger); ok {
+		return l
+	}
+	return slog.Default()
+}
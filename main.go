@@ -2,50 +2,24 @@ package main
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"log"
+	"log/slog"
 	"net/http"
 	"os"
-	"sync"
 	"time"
 
+	"github.com/NLstn/todo_backend/auth"
+	"github.com/NLstn/todo_backend/middleware"
+	"github.com/NLstn/todo_backend/router"
+	"github.com/NLstn/todo_backend/ui"
 	"github.com/joho/godotenv"
-	"go.mongodb.org/mongo-driver/bson"
-	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
-type Todo struct {
-	ID          primitive.ObjectID `bson:"_id,omitempty" json:"id,omitempty"`
-	Description string             `json:"description"`
-}
-
-var mutex = &sync.Mutex{}
-
-type wrappedWriter struct {
-	http.ResponseWriter
-	statusCode int
-}
-
-func (w *wrappedWriter) WriteHeader(statusCode int) {
-	w.statusCode = statusCode
-	w.ResponseWriter.WriteHeader(statusCode)
-}
-
-func Logging(next http.Handler) http.Handler {
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		start := time.Now()
-
-		wrapped := &wrappedWriter{ResponseWriter: w, statusCode: http.StatusOK}
-
-		next.ServeHTTP(wrapped, r)
-		log.Println(wrapped.statusCode, r.Method, r.URL.Path, time.Since(start))
-	})
-}
-
 func main() {
+	slog.SetDefault(slog.New(slog.NewJSONHandler(os.Stdout, nil)))
 
 	err := godotenv.Load()
 	if err != nil {
@@ -57,164 +31,52 @@ func main() {
 		log.Fatal("MONGO_URI not set in .env file")
 	}
 
-	client, err := mongo.Connect(context.TODO(), options.Client().ApplyURI(mongoURI))
-	if err != nil {
-		log.Fatal(err)
+	jwtSecret := os.Getenv("JWT_SECRET")
+	if jwtSecret == "" {
+		log.Fatal("JWT_SECRET not set in .env file")
 	}
 
-	mux := http.NewServeMux()
-
-	collection := client.Database("todos").Collection("todos")
-
-	mux.HandleFunc("POST /v1/todos", func(w http.ResponseWriter, r *http.Request) {
-		w.Header().Add("Access-Control-Allow-Origin", "*")
-
-		var todo Todo
-		err := json.NewDecoder(r.Body).Decode(&todo)
-		if err != nil {
-			http.Error(w, err.Error(), http.StatusBadRequest)
-			return
-		}
-		defer r.Body.Close()
-
-		mutex.Lock()
-		result, err := collection.InsertOne(context.TODO(), bson.M{"description": todo.Description})
-		if err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
-			return
-		}
-		mutex.Unlock()
-		todo.ID = result.InsertedID.(primitive.ObjectID)
-
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusCreated)
-		json.NewEncoder(w).Encode(todo)
-	})
-
-	mux.HandleFunc("GET /v1/todos/{id}", func(w http.ResponseWriter, r *http.Request) {
-		w.Header().Add("Access-Control-Allow-Origin", "*")
-
-		idStr := r.PathValue("id")
-		if idStr == "" {
-			http.Error(w, "id is required", http.StatusBadRequest)
-			return
-		}
-
-		id, err := primitive.ObjectIDFromHex(idStr)
-		if err != nil {
-			http.Error(w, "id must be an integer", http.StatusBadRequest)
-			return
-		}
-
-		var todo Todo
-		err = collection.FindOne(context.TODO(), bson.M{"_id": id}).Decode(&todo)
-		if err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
-			return
-		}
-
-		w.Header().Set("Content-Type", "application/json")
-		json.NewEncoder(w).Encode(todo)
-	})
-
-	mux.HandleFunc("PATCH /v1/todos/{id}", func(w http.ResponseWriter, r *http.Request) {
-		w.Header().Add("Access-Control-Allow-Origin", "*")
-
-		id, err := primitive.ObjectIDFromHex(r.PathValue("id"))
-		if err != nil {
-			http.Error(w, "Invalid ID", http.StatusBadRequest)
-			return
-		}
-
-		var todo Todo
-		err = json.NewDecoder(r.Body).Decode(&todo)
-		if err != nil {
-			http.Error(w, err.Error(), http.StatusBadRequest)
-			return
-		}
-		defer r.Body.Close()
-
-		var updatedTodo Todo
-		err = collection.FindOneAndUpdate(
-			context.TODO(),
-			bson.M{"_id": id},
-			bson.M{"$set": bson.M{"description": todo.Description}},
-			options.FindOneAndUpdate().SetReturnDocument(options.After),
-		).Decode(&updatedTodo)
+	jwtTTL := 24 * time.Hour
+	if ttlStr := os.Getenv("JWT_TTL"); ttlStr != "" {
+		jwtTTL, err = time.ParseDuration(ttlStr)
 		if err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
-			return
+			log.Fatal("JWT_TTL must be a valid duration: ", err)
 		}
+	}
 
-		w.Header().Set("Content-Type", "application/json")
-		json.NewEncoder(w).Encode(updatedTodo)
-	})
-
-	mux.HandleFunc("DELETE /v1/todos/{id}", func(w http.ResponseWriter, r *http.Request) {
-		w.Header().Add("Access-Control-Allow-Origin", "*")
-
-		id, err := primitive.ObjectIDFromHex(r.PathValue("id"))
-		if err != nil {
-			http.Error(w, "Invalid ID", http.StatusBadRequest)
-			return
-		}
-
-		result, err := collection.DeleteOne(context.TODO(), bson.M{"_id": id})
-		if err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
-			return
-		}
-
-		if result.DeletedCount == 0 {
-			http.Error(w, "todo not found", http.StatusNotFound)
-			return
-		}
-
-		w.WriteHeader(http.StatusNoContent)
-	})
-
-	mux.HandleFunc("GET /v1/todos", func(w http.ResponseWriter, r *http.Request) {
-		w.Header().Add("Access-Control-Allow-Origin", "*")
-
-		rows, err := collection.Find(context.TODO(), bson.M{})
-		if err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
-			return
-		}
-		defer rows.Close(context.TODO())
+	csrfSecret := os.Getenv("CSRF_SECRET")
+	if csrfSecret == "" {
+		log.Fatal("CSRF_SECRET not set in .env file")
+	}
 
-		var todos []Todo
-		for rows.Next(context.TODO()) {
-			var todo Todo
-			err := rows.Decode(&todo)
-			if err != nil {
-				http.Error(w, err.Error(), http.StatusInternalServerError)
-				return
-			}
-			todos = append(todos, todo)
-		}
+	client, err := mongo.Connect(context.TODO(), options.Client().ApplyURI(mongoURI))
+	if err != nil {
+		log.Fatal(err)
+	}
 
-		w.Header().Set("Content-Type", "application/json")
-		json.NewEncoder(w).Encode(todos)
-	})
+	collection := client.Database("todos").Collection("todos")
+	users := client.Database("todos").Collection("users")
+	authService := auth.NewService(users, jwtSecret, jwtTTL)
+	if err := authService.EnsureIndexes(context.TODO()); err != nil {
+		log.Fatal(err)
+	}
 
-	mux.HandleFunc("OPTIONS /v1/todos", func(w http.ResponseWriter, r *http.Request) {
-		w.Header().Add("Access-Control-Allow-Origin", "*")
-		w.Header().Add("Access-Control-Allow-Methods", "GET, POST, PATCH, DELETE")
-		w.Header().Add("Access-Control-Allow-Headers", "Content-Type")
-		w.WriteHeader(http.StatusOK)
-	})
+	uiHandler, err := ui.NewHandler(collection, authService, csrfSecret, "templates")
+	if err != nil {
+		log.Fatal(err)
+	}
 
-	mux.HandleFunc("OPTIONS /v1/todos/{id}", func(w http.ResponseWriter, r *http.Request) {
-		w.Header().Add("Access-Control-Allow-Origin", "*")
-		w.Header().Add("Access-Control-Allow-Methods", "GET, PATCH, DELETE")
-		w.Header().Add("Access-Control-Allow-Headers", "Content-Type")
-		w.WriteHeader(http.StatusOK)
+	r := router.New(router.Dependencies{
+		Todos: collection,
+		Auth:  authService,
+		Mongo: client,
+		UI:    uiHandler,
+		CORS:  middleware.CORSConfigFromEnv(os.Getenv),
 	})
 
 	server := &http.Server{
 		Addr:    "localhost:8080",
-		Handler: Logging(mux),
+		Handler: middleware.RequestID(middleware.Logging(r)),
 	}
 
 	fmt.Println("Server running on port 8080")
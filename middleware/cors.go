@@ -0,0 +1,99 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CORSConfig controls which cross-origin requests CORS will allow.
+type CORSConfig struct {
+	AllowedOrigins   []string
+	AllowedMethods   []string
+	AllowedHeaders   []string
+	ExposedHeaders   []string
+	AllowCredentials bool
+	MaxAge           time.Duration
+}
+
+// CORSConfigFromEnv builds a CORSConfig from CORS_ALLOWED_ORIGINS,
+// CORS_ALLOWED_METHODS, CORS_ALLOWED_HEADERS, CORS_EXPOSED_HEADERS,
+// CORS_ALLOW_CREDENTIALS and CORS_MAX_AGE, each a comma-separated list
+// except the latter two.
+func CORSConfigFromEnv(getenv func(string) string) CORSConfig {
+	maxAge, _ := time.ParseDuration(getenv("CORS_MAX_AGE"))
+	allowCredentials, _ := strconv.ParseBool(getenv("CORS_ALLOW_CREDENTIALS"))
+
+	return CORSConfig{
+		AllowedOrigins:   splitCSV(getenv("CORS_ALLOWED_ORIGINS")),
+		AllowedMethods:   splitCSV(getenv("CORS_ALLOWED_METHODS")),
+		AllowedHeaders:   splitCSV(getenv("CORS_ALLOWED_HEADERS")),
+		ExposedHeaders:   splitCSV(getenv("CORS_EXPOSED_HEADERS")),
+		AllowCredentials: allowCredentials,
+		MaxAge:           maxAge,
+	}
+}
+
+// CORS returns middleware that handles CORS per cfg: it echoes back a
+// matching request Origin (rather than always emitting "*", so it also
+// works with AllowCredentials), answers preflight OPTIONS requests with a
+// 204 for any route, and sets Vary: Origin.
+func CORS(cfg CORSConfig) func(http.Handler) http.Handler {
+	allowedMethods := strings.Join(cfg.AllowedMethods, ", ")
+	allowedHeaders := strings.Join(cfg.AllowedHeaders, ", ")
+	exposedHeaders := strings.Join(cfg.ExposedHeaders, ", ")
+	maxAge := strconv.Itoa(int(cfg.MaxAge.Seconds()))
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Add("Vary", "Origin")
+
+			origin := r.Header.Get("Origin")
+			if origin != "" && originAllowed(cfg.AllowedOrigins, origin) {
+				w.Header().Set("Access-Control-Allow-Origin", origin)
+				if cfg.AllowCredentials {
+					w.Header().Set("Access-Control-Allow-Credentials", "true")
+				}
+				if exposedHeaders != "" {
+					w.Header().Set("Access-Control-Expose-Headers", exposedHeaders)
+				}
+			}
+
+			if r.Method == http.MethodOptions {
+				w.Header().Set("Access-Control-Allow-Methods", allowedMethods)
+				w.Header().Set("Access-Control-Allow-Headers", allowedHeaders)
+				if cfg.MaxAge > 0 {
+					w.Header().Set("Access-Control-Max-Age", maxAge)
+				}
+				w.WriteHeader(http.StatusNoContent)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+func originAllowed(allowed []string, origin string) bool {
+	for _, o := range allowed {
+		if o == "*" || o == origin {
+			return true
+		}
+	}
+	return false
+}
+
+func splitCSV(s string) []string {
+	if s == "" {
+		return nil
+	}
+	parts := strings.Split(s, ",")
+	values := make([]string, 0, len(parts))
+	for _, p := range parts {
+		if p = strings.TrimSpace(p); p != "" {
+			values = append(values, p)
+		}
+	}
+	return values
+}
@@ -0,0 +1,51 @@
+package middleware
+
+import "testing"
+
+func TestOriginAllowed(t *testing.T) {
+	tests := []struct {
+		name    string
+		allowed []string
+		origin  string
+		want    bool
+	}{
+		{name: "exact match", allowed: []string{"https://example.com"}, origin: "https://example.com", want: true},
+		{name: "no match", allowed: []string{"https://example.com"}, origin: "https://evil.com", want: false},
+		{name: "wildcard allows anything", allowed: []string{"*"}, origin: "https://evil.com", want: true},
+		{name: "empty allowlist", allowed: nil, origin: "https://example.com", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := originAllowed(tt.allowed, tt.origin); got != tt.want {
+				t.Errorf("originAllowed(%v, %q) = %v, want %v", tt.allowed, tt.origin, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSplitCSV(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want []string
+	}{
+		{name: "empty", in: "", want: nil},
+		{name: "single", in: "GET", want: []string{"GET"}},
+		{name: "multiple with spaces", in: "GET, POST , DELETE", want: []string{"GET", "POST", "DELETE"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := splitCSV(tt.in)
+			if len(got) != len(tt.want) {
+				t.Fatalf("splitCSV(%q) = %v, want %v", tt.in, got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("splitCSV(%q)[%d] = %q, want %q", tt.in, i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,43 @@
+// Package middleware holds cross-cutting net/http middleware shared by
+// every route: request ID propagation, access logging and CORS.
+package middleware
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+
+	"github.com/NLstn/todo_backend/logger"
+	"github.com/google/uuid"
+)
+
+// RequestIDHeader is the header used to read and echo back the request id.
+const RequestIDHeader = "X-Request-ID"
+
+type contextKey int
+
+const requestIDContextKey contextKey = iota
+
+// RequestID assigns each request a unique id (reusing one supplied by the
+// caller via the X-Request-ID header), echoes it back on the response,
+// and stores both the id and a logger scoped to it in the request context.
+func RequestID(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get(RequestIDHeader)
+		if id == "" {
+			id = uuid.NewString()
+		}
+		w.Header().Set(RequestIDHeader, id)
+
+		ctx := context.WithValue(r.Context(), requestIDContextKey, id)
+		ctx = logger.NewContext(ctx, slog.Default().With("request_id", id))
+
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// RequestIDFromContext returns the request id stored by RequestID, if any.
+func RequestIDFromContext(ctx context.Context) (string, bool) {
+	id, ok := ctx.Value(requestIDContextKey).(string)
+	return id, ok
+}
@@ -0,0 +1,45 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRequestID(t *testing.T) {
+	tests := []struct {
+		name      string
+		incoming  string
+		wantEcho  bool
+		wantEmpty bool
+	}{
+		{name: "generates an id when none supplied", incoming: "", wantEmpty: false},
+		{name: "echoes back an incoming id", incoming: "req-123", wantEcho: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotID string
+			handler := RequestID(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				gotID, _ = RequestIDFromContext(r.Context())
+			}))
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.incoming != "" {
+				req.Header.Set(RequestIDHeader, tt.incoming)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			if gotID == "" {
+				t.Fatal("expected a request id in context")
+			}
+			if rec.Header().Get(RequestIDHeader) != gotID {
+				t.Errorf("response header %q = %q, want %q", RequestIDHeader, rec.Header().Get(RequestIDHeader), gotID)
+			}
+			if tt.wantEcho && gotID != tt.incoming {
+				t.Errorf("request id = %q, want echoed %q", gotID, tt.incoming)
+			}
+		})
+	}
+}
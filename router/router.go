@@ -0,0 +1,64 @@
+// Package router wires the HTTP routes for the service onto a
+// gorilla/mux router, including path patterns plain net/http cannot
+// express (regex-constrained path segments, nested sub-collections).
+package router
+
+import (
+	"net/http"
+
+	"github.com/NLstn/todo_backend/auth"
+	"github.com/NLstn/todo_backend/handlers"
+	"github.com/NLstn/todo_backend/middleware"
+	"github.com/NLstn/todo_backend/ui"
+	"github.com/gorilla/mux"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// Dependencies are the services the router dispatches requests to.
+type Dependencies struct {
+	Todos *mongo.Collection
+	Auth  *auth.Service
+	Mongo *mongo.Client
+	UI    *ui.Handler
+	CORS  middleware.CORSConfig
+}
+
+// New builds the application's router.
+func New(deps Dependencies) *mux.Router {
+	r := mux.NewRouter()
+	r.Use(middleware.CORS(deps.CORS))
+
+	r.HandleFunc("/healthz", handlers.Healthz).Methods(http.MethodGet, http.MethodOptions)
+	r.HandleFunc("/readyz", handlers.Readyz(deps.Mongo)).Methods(http.MethodGet, http.MethodOptions)
+
+	r.HandleFunc("/v1/auth/register", deps.Auth.Register).Methods(http.MethodPost, http.MethodOptions)
+	r.HandleFunc("/v1/auth/login", deps.Auth.Login).Methods(http.MethodPost, http.MethodOptions)
+
+	todoHandler := handlers.NewTodoHandler(deps.Todos)
+
+	todos := r.PathPrefix("/v1/todos").Subrouter()
+	todos.Use(deps.Auth.Authenticate)
+
+	todos.HandleFunc("", todoHandler.List).Methods(http.MethodGet, http.MethodOptions)
+	todos.HandleFunc("", todoHandler.Create).Methods(http.MethodPost, http.MethodOptions)
+	todos.HandleFunc("/{id:[a-f0-9]{24}}", todoHandler.Get).Methods(http.MethodGet, http.MethodOptions)
+	todos.HandleFunc("/{id:[a-f0-9]{24}}", todoHandler.Update).Methods(http.MethodPatch, http.MethodOptions)
+	todos.HandleFunc("/{id:[a-f0-9]{24}}", todoHandler.Delete).Methods(http.MethodDelete, http.MethodOptions)
+	todos.HandleFunc("/{id:[a-f0-9]{24}}/tags/{tag}", todoHandler.GetTag).Methods(http.MethodGet, http.MethodOptions)
+	todos.HandleFunc("/{id:[a-f0-9]{24}}/tags/{tag}", todoHandler.SetTag).Methods(http.MethodPost, http.MethodOptions)
+
+	r.HandleFunc("/login", deps.UI.LoginForm).Methods(http.MethodGet, http.MethodOptions)
+	r.HandleFunc("/login", deps.UI.Login).Methods(http.MethodPost, http.MethodOptions)
+	r.HandleFunc("/register", deps.UI.RegisterForm).Methods(http.MethodGet, http.MethodOptions)
+	r.HandleFunc("/register", deps.UI.Register).Methods(http.MethodPost, http.MethodOptions)
+	r.HandleFunc("/logout", deps.UI.Logout).Methods(http.MethodPost, http.MethodOptions)
+
+	ui := r.NewRoute().Subrouter()
+	ui.Use(deps.Auth.Authenticate)
+	ui.HandleFunc("/", deps.UI.List).Methods(http.MethodGet, http.MethodOptions)
+	ui.HandleFunc("/todos", deps.UI.Create).Methods(http.MethodPost, http.MethodOptions)
+	ui.HandleFunc("/todos/{id:[a-f0-9]{24}}", deps.UI.Info).Methods(http.MethodGet, http.MethodOptions)
+	ui.HandleFunc("/todos/{id:[a-f0-9]{24}}", deps.UI.Submit).Methods(http.MethodPost, http.MethodOptions)
+
+	return r
+}
@@ -0,0 +1,52 @@
+package router
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/NLstn/todo_backend/auth"
+	"github.com/NLstn/todo_backend/middleware"
+	"github.com/NLstn/todo_backend/ui"
+)
+
+// TestCORSPreflight guards against a route matching a path with no handler
+// for OPTIONS: gorilla/mux only builds the middleware chain (and so only
+// runs middleware.CORS) once a route actually matches, so every OPTIONS
+// route must be registered explicitly rather than relying on r.Use alone.
+func TestCORSPreflight(t *testing.T) {
+	authService := auth.NewService(nil, "test-secret", time.Hour)
+	uiHandler, err := ui.NewHandler(nil, authService, "csrf-secret", "../templates")
+	if err != nil {
+		t.Fatalf("ui.NewHandler() error = %v", err)
+	}
+
+	r := New(Dependencies{
+		Auth: authService,
+		UI:   uiHandler,
+		CORS: middleware.CORSConfig{
+			AllowedOrigins: []string{"https://example.com"},
+			AllowedMethods: []string{"GET", "POST", "PATCH", "DELETE"},
+		},
+	})
+
+	paths := []string{"/healthz", "/v1/auth/login", "/v1/todos", "/v1/todos/000000000000000000000000", "/"}
+
+	for _, path := range paths {
+		t.Run(path, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodOptions, path, nil)
+			req.Header.Set("Origin", "https://example.com")
+			rec := httptest.NewRecorder()
+
+			r.ServeHTTP(rec, req)
+
+			if rec.Code != http.StatusNoContent {
+				t.Fatalf("OPTIONS %s status = %d, want %d", path, rec.Code, http.StatusNoContent)
+			}
+			if got := rec.Header().Get("Access-Control-Allow-Origin"); got != "https://example.com" {
+				t.Errorf("OPTIONS %s Access-Control-Allow-Origin = %q, want %q", path, got, "https://example.com")
+			}
+		})
+	}
+}
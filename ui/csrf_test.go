@@ -0,0 +1,28 @@
+package ui
+
+import "testing"
+
+func TestVerifyCSRFToken(t *testing.T) {
+	secret := []byte("test-secret")
+	token := csrfToken(secret, "nonce-1")
+
+	tests := []struct {
+		name  string
+		token string
+		want  bool
+	}{
+		{name: "valid token", token: token, want: true},
+		{name: "tampered nonce", token: "nonce-2" + token[len("nonce-1"):], want: false},
+		{name: "signed with a different secret", token: csrfToken([]byte("other-secret"), "nonce-1"), want: false},
+		{name: "malformed token", token: "not-a-token", want: false},
+		{name: "empty token", token: "", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := verifyCSRFToken(secret, tt.token); got != tt.want {
+				t.Errorf("verifyCSRFToken(%q) = %v, want %v", tt.token, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,345 @@
+// Package ui serves a minimal server-rendered, zero-JS interface for
+// browsing and editing todos, backed by the same Mongo collection as the
+// JSON API under /v1/todos.
+package ui
+
+import (
+	"html/template"
+	"net/http"
+	"path/filepath"
+	"time"
+
+	"github.com/NLstn/todo_backend/auth"
+	"github.com/NLstn/todo_backend/handlers"
+	"github.com/NLstn/todo_backend/logger"
+	"github.com/gorilla/mux"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Handler serves the HTML UI routes against a single Mongo collection.
+type Handler struct {
+	Collection *mongo.Collection
+	Auth       *auth.Service
+	CSRFSecret []byte
+
+	listTemplate     *template.Template
+	infoTemplate     *template.Template
+	loginTemplate    *template.Template
+	registerTemplate *template.Template
+}
+
+// NewHandler parses the templates under templatesDir and builds a Handler
+// backed by collection, authenticating browser sessions through authService.
+func NewHandler(collection *mongo.Collection, authService *auth.Service, csrfSecret string, templatesDir string) (*Handler, error) {
+	base := filepath.Join(templatesDir, "base.html")
+
+	listTemplate, err := template.ParseFiles(base, filepath.Join(templatesDir, "todos-list.html"))
+	if err != nil {
+		return nil, err
+	}
+
+	infoTemplate, err := template.ParseFiles(base, filepath.Join(templatesDir, "todo-info.html"))
+	if err != nil {
+		return nil, err
+	}
+
+	loginTemplate, err := template.ParseFiles(base, filepath.Join(templatesDir, "login.html"))
+	if err != nil {
+		return nil, err
+	}
+
+	registerTemplate, err := template.ParseFiles(base, filepath.Join(templatesDir, "register.html"))
+	if err != nil {
+		return nil, err
+	}
+
+	return &Handler{
+		Collection:       collection,
+		Auth:             authService,
+		CSRFSecret:       []byte(csrfSecret),
+		listTemplate:     listTemplate,
+		infoTemplate:     infoTemplate,
+		loginTemplate:    loginTemplate,
+		registerTemplate: registerTemplate,
+	}, nil
+}
+
+type listData struct {
+	Title     string
+	Todos     []handlers.Todo
+	CSRFToken string
+}
+
+type infoData struct {
+	Title     string
+	Todo      handlers.Todo
+	CSRFToken string
+}
+
+type authFormData struct {
+	Title     string
+	CSRFToken string
+	Error     string
+}
+
+// List handles GET /.
+func (h *Handler) List(w http.ResponseWriter, r *http.Request) {
+	ownerID, _ := auth.UserIDFromContext(r.Context())
+
+	rows, err := h.Collection.Find(r.Context(), bson.M{"owner_id": ownerID})
+	if err != nil {
+		logger.FromContext(r.Context()).Error("list todos failed", "error", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	defer rows.Close(r.Context())
+
+	var todos []handlers.Todo
+	for rows.Next(r.Context()) {
+		var todo handlers.Todo
+		if err := rows.Decode(&todo); err != nil {
+			logger.FromContext(r.Context()).Error("decode todo failed", "error", err)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		todos = append(todos, todo)
+	}
+
+	token := h.ensureCSRFCookie(w, r)
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if err := h.listTemplate.ExecuteTemplate(w, "base", listData{Title: "Todos", Todos: todos, CSRFToken: token}); err != nil {
+		logger.FromContext(r.Context()).Error("render todo list failed", "error", err)
+	}
+}
+
+// Info handles GET /todos/{id}.
+func (h *Handler) Info(w http.ResponseWriter, r *http.Request) {
+	ownerID, _ := auth.UserIDFromContext(r.Context())
+
+	id, err := primitive.ObjectIDFromHex(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "id must be an object id", http.StatusBadRequest)
+		return
+	}
+
+	var todo handlers.Todo
+	err = h.Collection.FindOne(r.Context(), bson.M{"_id": id}).Decode(&todo)
+	if handlers.WriteFindErr(w, r, err) {
+		return
+	}
+	if todo.OwnerID != ownerID {
+		http.Error(w, "forbidden", http.StatusForbidden)
+		return
+	}
+
+	token := h.ensureCSRFCookie(w, r)
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if err := h.infoTemplate.ExecuteTemplate(w, "base", infoData{Title: todo.Description, Todo: todo, CSRFToken: token}); err != nil {
+		logger.FromContext(r.Context()).Error("render todo info failed", "error", err)
+	}
+}
+
+// Create handles POST /todos.
+func (h *Handler) Create(w http.ResponseWriter, r *http.Request) {
+	ownerID, _ := auth.UserIDFromContext(r.Context())
+
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if !h.checkCSRF(r) {
+		http.Error(w, "invalid csrf token", http.StatusForbidden)
+		return
+	}
+
+	_, err := h.Collection.InsertOne(r.Context(), bson.M{"description": r.FormValue("description"), "owner_id": ownerID})
+	if err != nil {
+		logger.FromContext(r.Context()).Error("insert todo failed", "error", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	http.Redirect(w, r, "/", http.StatusSeeOther)
+}
+
+// Submit handles POST /todos/{id}, dispatching to an update or delete based
+// on the form-encoded _method override field since HTML forms can't send
+// PATCH or DELETE directly.
+func (h *Handler) Submit(w http.ResponseWriter, r *http.Request) {
+	ownerID, _ := auth.UserIDFromContext(r.Context())
+
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if !h.checkCSRF(r) {
+		http.Error(w, "invalid csrf token", http.StatusForbidden)
+		return
+	}
+
+	id, err := primitive.ObjectIDFromHex(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "id must be an object id", http.StatusBadRequest)
+		return
+	}
+
+	var existing handlers.Todo
+	err = h.Collection.FindOne(r.Context(), bson.M{"_id": id}).Decode(&existing)
+	if handlers.WriteFindErr(w, r, err) {
+		return
+	}
+	if existing.OwnerID != ownerID {
+		http.Error(w, "forbidden", http.StatusForbidden)
+		return
+	}
+
+	switch r.FormValue("_method") {
+	case http.MethodDelete:
+		if _, err := h.Collection.DeleteOne(r.Context(), bson.M{"_id": id, "owner_id": ownerID}); err != nil {
+			logger.FromContext(r.Context()).Error("delete todo failed", "error", err)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		http.Redirect(w, r, "/", http.StatusSeeOther)
+
+	case http.MethodPatch:
+		_, err := h.Collection.UpdateOne(
+			r.Context(),
+			bson.M{"_id": id, "owner_id": ownerID},
+			bson.M{"$set": bson.M{"description": r.FormValue("description")}},
+			options.Update(),
+		)
+		if err != nil {
+			logger.FromContext(r.Context()).Error("update todo failed", "error", err)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		http.Redirect(w, r, "/todos/"+id.Hex(), http.StatusSeeOther)
+
+	default:
+		http.Error(w, "unsupported _method", http.StatusBadRequest)
+	}
+}
+
+// LoginForm handles GET /login.
+func (h *Handler) LoginForm(w http.ResponseWriter, r *http.Request) {
+	token := h.ensureCSRFCookie(w, r)
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if err := h.loginTemplate.ExecuteTemplate(w, "base", authFormData{Title: "Log in", CSRFToken: token}); err != nil {
+		logger.FromContext(r.Context()).Error("render login form failed", "error", err)
+	}
+}
+
+// Login handles POST /login, setting the auth cookie on success so
+// subsequent requests through the UI are authenticated.
+func (h *Handler) Login(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if !h.checkCSRF(r) {
+		http.Error(w, "invalid csrf token", http.StatusForbidden)
+		return
+	}
+
+	userID, err := h.Auth.VerifyCredentials(r.Context(), r.FormValue("email"), r.FormValue("password"))
+	if err != nil {
+		token := h.ensureCSRFCookie(w, r)
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.WriteHeader(http.StatusUnauthorized)
+		h.loginTemplate.ExecuteTemplate(w, "base", authFormData{Title: "Log in", CSRFToken: token, Error: "invalid email or password"})
+		return
+	}
+
+	tokenStr, err := h.Auth.IssueToken(userID)
+	if err != nil {
+		logger.FromContext(r.Context()).Error("issue token failed", "error", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	h.setAuthCookie(w, tokenStr)
+	http.Redirect(w, r, "/", http.StatusSeeOther)
+}
+
+// RegisterForm handles GET /register.
+func (h *Handler) RegisterForm(w http.ResponseWriter, r *http.Request) {
+	token := h.ensureCSRFCookie(w, r)
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if err := h.registerTemplate.ExecuteTemplate(w, "base", authFormData{Title: "Register", CSRFToken: token}); err != nil {
+		logger.FromContext(r.Context()).Error("render register form failed", "error", err)
+	}
+}
+
+// Register handles POST /register, creating the user and logging them in.
+func (h *Handler) Register(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if !h.checkCSRF(r) {
+		http.Error(w, "invalid csrf token", http.StatusForbidden)
+		return
+	}
+
+	userID, err := h.Auth.CreateUser(r.Context(), r.FormValue("email"), r.FormValue("password"))
+	if err != nil {
+		msg := "could not register"
+		if mongo.IsDuplicateKeyError(err) {
+			msg = "email already registered"
+		} else {
+			logger.FromContext(r.Context()).Error("create user failed", "error", err)
+		}
+		token := h.ensureCSRFCookie(w, r)
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.WriteHeader(http.StatusConflict)
+		h.registerTemplate.ExecuteTemplate(w, "base", authFormData{Title: "Register", CSRFToken: token, Error: msg})
+		return
+	}
+
+	tokenStr, err := h.Auth.IssueToken(userID)
+	if err != nil {
+		logger.FromContext(r.Context()).Error("issue token failed", "error", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	h.setAuthCookie(w, tokenStr)
+	http.Redirect(w, r, "/", http.StatusSeeOther)
+}
+
+// Logout handles POST /logout, clearing the auth cookie.
+func (h *Handler) Logout(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if !h.checkCSRF(r) {
+		http.Error(w, "invalid csrf token", http.StatusForbidden)
+		return
+	}
+
+	http.SetCookie(w, &http.Cookie{
+		Name:     auth.CookieName,
+		Value:    "",
+		Path:     "/",
+		HttpOnly: true,
+		SameSite: http.SameSiteStrictMode,
+		MaxAge:   -1,
+	})
+	http.Redirect(w, r, "/login", http.StatusSeeOther)
+}
+
+func (h *Handler) setAuthCookie(w http.ResponseWriter, tokenStr string) {
+	http.SetCookie(w, &http.Cookie{
+		Name:     auth.CookieName,
+		Value:    tokenStr,
+		Path:     "/",
+		HttpOnly: true,
+		SameSite: http.SameSiteStrictMode,
+		Expires:  time.Now().Add(h.Auth.TTL),
+	})
+}